@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	internalreddit "github.com/tarun1820/go-learnings/reddit/internal/reddit"
+	internalseen "github.com/tarun1820/go-learnings/reddit/internal/seen"
+)
+
+// defaultSchedule is used when SCHEDULE is unset: every 10 minutes.
+const defaultSchedule = "*/10 * * * *"
+
+// runDaemon runs the monitor as a long-lived process, firing runOnce on
+// the cron schedule from the SCHEDULE env var and serving Prometheus
+// metrics/health checks on METRICS_PORT until ctx is cancelled (e.g. by
+// SIGINT or SIGTERM). cron.SkipIfStillRunning guards against a run that
+// outlasts the schedule interval overlapping the next one, and ctx is
+// passed into runOnce so a cancellation aborts an in-flight run instead
+// of leaving it to finish on its own.
+func runDaemon(ctx context.Context, cfg *Config, rulesPath string, client *internalreddit.Client, store internalseen.Store, metrics *Metrics) error {
+	schedule := envOrDefault("SCHEDULE", defaultSchedule)
+
+	c := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	_, err := c.AddFunc(schedule, func() {
+		if err := runOnce(ctx, cfg, rulesPath, client, store, metrics); err != nil {
+			log.Printf("Run failed: %v", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid SCHEDULE %q: %w", schedule, err)
+	}
+	c.Start()
+	log.Printf("Daemon started, running on schedule %q", schedule)
+
+	var server *http.Server
+	if port := os.Getenv("METRICS_PORT"); port != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		mux.HandleFunc("/healthz", healthzHandler)
+		server = &http.Server{Addr: ":" + port, Handler: mux}
+
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Metrics server error: %v", err)
+			}
+		}()
+		log.Printf("Metrics server listening on :%s", port)
+	}
+
+	<-ctx.Done()
+	log.Println("Shutdown signal received, stopping gracefully...")
+
+	// Stop the scheduler and wait for any in-flight run to return before
+	// shutting down the metrics server it reports to.
+	<-c.Stop().Done()
+
+	if server != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Metrics server shutdown error: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"time"
+)
+
+// EmailAttachment is a file attached to an EmailMessage, base64-encoded
+// into its own MIME part.
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// EmailMessage is a fully composed email: a plain-text and HTML
+// rendering of the same content (multipart/alternative), plus any
+// attachments (multipart/mixed).
+type EmailMessage struct {
+	To          string
+	Subject     string
+	Body        string
+	HTMLBody    string
+	Attachments []EmailAttachment
+}
+
+// defaultHTMLTemplate renders the HTML part when Config.EmailTemplatePath
+// is unset.
+const defaultHTMLTemplate = `<html><body>
+<ul>
+{{range .Posts}}<li><a href="{{.Permalink}}">{{.Title}}</a> &mdash; {{.Created.Format "Mon, 02 Jan 2006 15:04 MST"}}</li>
+{{end}}</ul>
+</body></html>`
+
+// emailTemplateData is what the HTML template is executed against.
+type emailTemplateData struct {
+	Posts []Post
+}
+
+// renderHTMLBody executes the template at templatePath (or
+// defaultHTMLTemplate if templatePath is empty) against posts. It renders
+// from the structured posts only, not the plain-text body, so the HTML
+// part doesn't duplicate the same listing twice under one flattened
+// paragraph.
+func renderHTMLBody(templatePath string, posts []Post) (string, error) {
+	var (
+		tmpl *template.Template
+		err  error
+	)
+	if templatePath != "" {
+		tmpl, err = template.ParseFiles(templatePath)
+	} else {
+		tmpl, err = template.New("email").Parse(defaultHTMLTemplate)
+	}
+	if err != nil {
+		return "", fmt.Errorf("parsing email template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, emailTemplateData{Posts: posts}); err != nil {
+		return "", fmt.Errorf("executing email template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// csvAttachment renders posts as a CSV attachment.
+func csvAttachment(filename string, posts []Post) (EmailAttachment, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write([]string{"Title", "Permalink", "Created (UTC)"}); err != nil {
+		return EmailAttachment{}, err
+	}
+	for _, post := range posts {
+		row := []string{sanitizeCSVField(post.Title), sanitizeCSVField(post.Permalink), post.Created.Format(time.RFC3339)}
+		if err := w.Write(row); err != nil {
+			return EmailAttachment{}, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return EmailAttachment{}, err
+	}
+
+	return EmailAttachment{Filename: filename, ContentType: "text/csv", Data: buf.Bytes()}, nil
+}
+
+// sanitizeCSVField neutralizes leading =, +, -, @, tab, and CR characters
+// that spreadsheet applications (Excel, Google Sheets) interpret as the
+// start of a formula, preventing CSV injection from post titles pulled
+// verbatim off the Reddit API.
+func sanitizeCSVField(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + s
+	default:
+		return s
+	}
+}
+
+// buildMIMEMessage renders msg as an RFC 5322 message: a
+// multipart/alternative text+HTML body, optionally wrapped in
+// multipart/mixed to carry attachments.
+func buildMIMEMessage(from string, msg EmailMessage) (string, error) {
+	var body bytes.Buffer
+
+	altWriter := multipart.NewWriter(&body)
+	if err := writeAlternativePart(altWriter, msg.Body, msg.HTMLBody); err != nil {
+		return "", err
+	}
+	if err := altWriter.Close(); err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	if len(msg.Attachments) == 0 {
+		fmt.Fprintf(&out, "From: %s\r\n", from)
+		fmt.Fprintf(&out, "To: %s\r\n", msg.To)
+		fmt.Fprintf(&out, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+		fmt.Fprintf(&out, "MIME-Version: 1.0\r\n")
+		fmt.Fprintf(&out, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", altWriter.Boundary())
+		out.Write(body.Bytes())
+		return out.String(), nil
+	}
+
+	mixedWriter := multipart.NewWriter(&out)
+	altPart, err := mixedWriter.CreatePart(map[string][]string{
+		"Content-Type": {fmt.Sprintf("multipart/alternative; boundary=%q", altWriter.Boundary())},
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err := altPart.Write(body.Bytes()); err != nil {
+		return "", err
+	}
+
+	for _, att := range msg.Attachments {
+		if err := writeAttachmentPart(mixedWriter, att); err != nil {
+			return "", err
+		}
+	}
+	if err := mixedWriter.Close(); err != nil {
+		return "", err
+	}
+
+	var final bytes.Buffer
+	fmt.Fprintf(&final, "From: %s\r\n", from)
+	fmt.Fprintf(&final, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&final, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&final, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&final, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mixedWriter.Boundary())
+	final.Write(out.Bytes())
+	return final.String(), nil
+}
+
+// writeAlternativePart writes the plain-text and HTML parts of a
+// multipart/alternative body.
+func writeAlternativePart(w *multipart.Writer, plain, html string) error {
+	plainPart, err := w.CreatePart(map[string][]string{
+		"Content-Type":              {`text/plain; charset="utf-8"`},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeQuotedPrintable(plainPart, plain); err != nil {
+		return err
+	}
+
+	htmlPart, err := w.CreatePart(map[string][]string{
+		"Content-Type":              {`text/html; charset="utf-8"`},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	return writeQuotedPrintable(htmlPart, html)
+}
+
+// writeAttachmentPart base64-encodes att into its own MIME part.
+func writeAttachmentPart(w *multipart.Writer, att EmailAttachment) error {
+	part, err := w.CreatePart(map[string][]string{
+		"Content-Type":              {att.ContentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {fmt.Sprintf(`attachment; filename=%q`, att.Filename)},
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(att.Data)
+	const lineLength = 76
+	for i := 0; i < len(encoded); i += lineLength {
+		end := i + lineLength
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		if _, err := fmt.Fprintf(part, "%s\r\n", encoded[i:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(s)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
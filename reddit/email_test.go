@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeCSVField(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "empty string is unchanged", in: "", want: ""},
+		{name: "plain title is unchanged", in: "Selling a couch", want: "Selling a couch"},
+		{name: "leading equals is escaped", in: "=cmd|' /C calc'!A1", want: "'=cmd|' /C calc'!A1"},
+		{name: "leading plus is escaped", in: "+1+1", want: "'+1+1"},
+		{name: "leading minus is escaped", in: "-2+3", want: "'-2+3"},
+		{name: "leading at is escaped", in: "@SUM(A1:A9)", want: "'@SUM(A1:A9)"},
+		{name: "leading tab is escaped", in: "\tmalicious", want: "'\tmalicious"},
+		{name: "leading carriage return is escaped", in: "\rmalicious", want: "'\rmalicious"},
+		{name: "formula character mid-string is left alone", in: "price = 5 each", want: "price = 5 each"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeCSVField(tt.in); got != tt.want {
+				t.Errorf("sanitizeCSVField(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildMIMEMessageWithoutAttachments(t *testing.T) {
+	msg := EmailMessage{
+		To:       "alerts@example.com",
+		Subject:  "Reddit Alert",
+		Body:     "plain body",
+		HTMLBody: "<p>html body</p>",
+	}
+
+	raw, err := buildMIMEMessage("monitor@example.com", msg)
+	if err != nil {
+		t.Fatalf("buildMIMEMessage() error = %v", err)
+	}
+
+	header, body := splitHeader(t, raw)
+	if !strings.Contains(header, "Content-Type: multipart/alternative") {
+		t.Errorf("expected a multipart/alternative message, got header:\n%s", header)
+	}
+
+	plain, html := readAlternativeParts(t, header, body)
+	if plain != "plain body" {
+		t.Errorf("plain part = %q, want %q", plain, "plain body")
+	}
+	if html != "<p>html body</p>" {
+		t.Errorf("html part = %q, want %q", html, "<p>html body</p>")
+	}
+}
+
+func TestBuildMIMEMessageWithAttachment(t *testing.T) {
+	msg := EmailMessage{
+		To:       "alerts@example.com",
+		Subject:  "Reddit Alert",
+		Body:     "plain body",
+		HTMLBody: "<p>html body</p>",
+		Attachments: []EmailAttachment{
+			{Filename: "matched_posts.csv", ContentType: "text/csv", Data: []byte("Title\r\nfoo\r\n")},
+		},
+	}
+
+	raw, err := buildMIMEMessage("monitor@example.com", msg)
+	if err != nil {
+		t.Fatalf("buildMIMEMessage() error = %v", err)
+	}
+
+	header, body := splitHeader(t, raw)
+	if !strings.Contains(header, "Content-Type: multipart/mixed") {
+		t.Errorf("expected a multipart/mixed message when attachments are present, got header:\n%s", header)
+	}
+
+	boundary := mimeBoundary(t, header)
+	mr := multipart.NewReader(strings.NewReader(body), boundary)
+
+	altPart, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("reading alternative part: %v", err)
+	}
+	if !strings.HasPrefix(altPart.Header.Get("Content-Type"), "multipart/alternative") {
+		t.Errorf("first part Content-Type = %q, want multipart/alternative", altPart.Header.Get("Content-Type"))
+	}
+
+	attPart, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("reading attachment part: %v", err)
+	}
+	if attPart.Header.Get("Content-Type") != "text/csv" {
+		t.Errorf("attachment Content-Type = %q, want text/csv", attPart.Header.Get("Content-Type"))
+	}
+	wantDisposition := `attachment; filename="matched_posts.csv"`
+	if got := attPart.Header.Get("Content-Disposition"); got != wantDisposition {
+		t.Errorf("attachment Content-Disposition = %q, want %q", got, wantDisposition)
+	}
+
+	encoded, err := io.ReadAll(attPart)
+	if err != nil {
+		t.Fatalf("reading attachment body: %v", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		t.Fatalf("decoding base64 attachment body: %v", err)
+	}
+	if string(decoded) != "Title\r\nfoo\r\n" {
+		t.Errorf("decoded attachment = %q, want %q", decoded, "Title\r\nfoo\r\n")
+	}
+}
+
+// splitHeader separates the RFC 5322 headers from the body of a message
+// built by buildMIMEMessage.
+func splitHeader(t *testing.T, raw string) (header, body string) {
+	t.Helper()
+	parts := strings.SplitN(raw, "\r\n\r\n", 2)
+	if len(parts) != 2 {
+		t.Fatalf("message has no header/body separator: %q", raw)
+	}
+	return parts[0], parts[1]
+}
+
+// mimeBoundary extracts the boundary parameter from a Content-Type header
+// line within header.
+func mimeBoundary(t *testing.T, header string) string {
+	t.Helper()
+	for _, line := range strings.Split(header, "\r\n") {
+		if !strings.HasPrefix(line, "Content-Type:") {
+			continue
+		}
+		_, params, err := mime.ParseMediaType(strings.TrimPrefix(line, "Content-Type: "))
+		if err != nil {
+			t.Fatalf("parsing Content-Type header %q: %v", line, err)
+		}
+		return params["boundary"]
+	}
+	t.Fatalf("no Content-Type header found in:\n%s", header)
+	return ""
+}
+
+// readAlternativeParts decodes the quoted-printable plain-text and HTML
+// parts of a top-level multipart/alternative message.
+func readAlternativeParts(t *testing.T, header, body string) (plain, html string) {
+	t.Helper()
+	boundary := mimeBoundary(t, header)
+	mr := multipart.NewReader(strings.NewReader(body), boundary)
+
+	plainPart, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("reading plain part: %v", err)
+	}
+	plain = decodeQuotedPrintablePart(t, plainPart)
+
+	htmlPart, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("reading html part: %v", err)
+	}
+	html = decodeQuotedPrintablePart(t, htmlPart)
+
+	return plain, html
+}
+
+func decodeQuotedPrintablePart(t *testing.T, part *multipart.Part) string {
+	t.Helper()
+	decoded, err := io.ReadAll(quotedprintable.NewReader(part))
+	if err != nil {
+		t.Fatalf("decoding quoted-printable part: %v", err)
+	}
+	return string(decoded)
+}
+
+func TestWriteAttachmentPartHeaders(t *testing.T) {
+	var body strings.Builder
+	w := multipart.NewWriter(&body)
+	if err := writeAttachmentPart(w, EmailAttachment{Filename: "report.csv", ContentType: "text/csv", Data: []byte("a,b,c")}); err != nil {
+		t.Fatalf("writeAttachmentPart() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing writer: %v", err)
+	}
+
+	mr := multipart.NewReader(strings.NewReader(body.String()), w.Boundary())
+	part, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("reading part: %v", err)
+	}
+	if got := textproto.MIMEHeader(part.Header).Get("Content-Transfer-Encoding"); got != "base64" {
+		t.Errorf("Content-Transfer-Encoding = %q, want base64", got)
+	}
+}
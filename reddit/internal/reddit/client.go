@@ -0,0 +1,286 @@
+package reddit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// tokenExpiryBuffer is how far ahead of the token's actual expiry
+// GetAccessToken proactively refreshes it.
+const tokenExpiryBuffer = 60 * time.Second
+
+// rateLimitBuffer is the minimum number of remaining requests in the
+// current rate-limit window before the client starts pacing itself.
+const rateLimitBuffer = 50
+
+// retryBackoff is the wait schedule between retries of a transient
+// failure (5xx or network error). The request is abandoned after the
+// schedule is exhausted.
+var retryBackoff = []time.Duration{200 * time.Millisecond, 500 * time.Millisecond, 1 * time.Second, 2 * time.Second}
+
+// ConnStats reports cumulative counters from the client's httptrace hook,
+// useful for confirming connection reuse under load.
+type ConnStats struct {
+	ConnsReused  int64
+	ConnsCreated int64
+}
+
+// Client is a rate-limit-aware Reddit API client. It caches the OAuth
+// token across calls, paces requests against the x-ratelimit-* response
+// headers, and retries transient failures with backoff.
+type Client struct {
+	clientID, clientSecret string
+	username, password     string
+	userAgent              string
+
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+
+	rateMu        sync.Mutex
+	rateRemaining int
+	rateReset     time.Time
+
+	connsReused  int64
+	connsCreated int64
+	requestCount int64
+}
+
+// NewClient builds a Client authenticating as the given script-app
+// credentials and Reddit account.
+func NewClient(clientID, clientSecret, username, password string) *Client {
+	c := &Client{
+		clientID:      clientID,
+		clientSecret:  clientSecret,
+		username:      username,
+		password:      password,
+		userAgent:     fmt.Sprintf("Go:HyderabadBuySellMonitor:v1.0 (by /u/%s)", username),
+		rateRemaining: -1, // unknown until the first response's headers are parsed
+	}
+
+	transport := &http.Transport{
+		MaxIdleConns:        50,
+		MaxIdleConnsPerHost: 10,
+		MaxConnsPerHost:     10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	c.httpClient = &http.Client{
+		Timeout:   15 * time.Second,
+		Transport: transport,
+	}
+
+	return c
+}
+
+// Stats returns cumulative connection-reuse counters observed via the
+// client's httptrace hook.
+func (c *Client) Stats() ConnStats {
+	return ConnStats{
+		ConnsReused:  atomic.LoadInt64(&c.connsReused),
+		ConnsCreated: atomic.LoadInt64(&c.connsCreated),
+	}
+}
+
+// RequestCount returns the cumulative number of HTTP requests this client
+// has issued against the Reddit API.
+func (c *Client) RequestCount() int64 {
+	return atomic.LoadInt64(&c.requestCount)
+}
+
+// RateLimitRemaining returns the remaining-requests count from the most
+// recently observed x-ratelimit-remaining header, or -1 if no response
+// has been seen yet.
+func (c *Client) RateLimitRemaining() int {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	return c.rateRemaining
+}
+
+// withTrace attaches an httptrace.ClientTrace to req that tallies
+// connection reuse into c.connsReused/c.connsCreated, and counts the
+// request itself.
+func (c *Client) withTrace(req *http.Request) *http.Request {
+	atomic.AddInt64(&c.requestCount, 1)
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				atomic.AddInt64(&c.connsReused, 1)
+			} else {
+				atomic.AddInt64(&c.connsCreated, 1)
+			}
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+// GetAccessToken returns a cached OAuth token, refreshing it first if it
+// is unset or within tokenExpiryBuffer of expiring.
+func (c *Client) GetAccessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://www.reddit.com/api/v1/access_token",
+		strings.NewReader("grant_type=password&username="+c.username+"&password="+c.password))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(c.clientID, c.clientSecret)
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(c.withTrace(req))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", ErrOAuthRevoked
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("reddit: failed to get token: %s", resp.Status)
+	}
+
+	var tokenResp AuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", err
+	}
+
+	c.token = tokenResp.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn)*time.Second - tokenExpiryBuffer)
+	return c.token, nil
+}
+
+// SubredditNew fetches the newest `limit` posts from the given
+// subreddit.
+func (c *Client) SubredditNew(ctx context.Context, name string, limit int) (*Listing, error) {
+	return c.SubredditListing(ctx, name, "new", limit)
+}
+
+// SubredditListing fetches a subreddit listing (e.g. "new", "hot", "top")
+// capped at `limit` posts, pacing itself against the rate limit and
+// retrying transient failures on a backoff schedule.
+func (c *Client) SubredditListing(ctx context.Context, name, sort string, limit int) (*Listing, error) {
+	token, err := c.GetAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://oauth.reddit.com/r/%s/%s?limit=%d", name, sort, limit)
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		if err := c.waitForRateLimit(ctx); err != nil {
+			return nil, err
+		}
+
+		listing, retry, err := c.doListingRequest(ctx, url, token)
+		if err == nil {
+			return listing, nil
+		}
+		if !retry {
+			return nil, err
+		}
+		lastErr = err
+
+		if attempt >= len(retryBackoff) {
+			return nil, fmt.Errorf("reddit: giving up after %d attempts: %w", attempt+1, lastErr)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryBackoff[attempt]):
+		}
+	}
+}
+
+// doListingRequest performs a single listing request, reporting whether
+// the error (if any) is worth retrying.
+func (c *Client) doListingRequest(ctx context.Context, url, token string) (listing *Listing, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Authorization", "bearer "+token)
+
+	resp, err := c.httpClient.Do(c.withTrace(req))
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+
+	c.recordRateLimit(resp.Header)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, false, ErrOAuthRevoked
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, false, ErrSubredditNotFound
+	case resp.StatusCode >= 500:
+		return nil, true, fmt.Errorf("reddit: server error: %s", resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("reddit: unexpected status: %s", resp.Status)
+	}
+
+	var l Listing
+	if err := json.NewDecoder(resp.Body).Decode(&l); err != nil {
+		return nil, false, err
+	}
+	return &l, false, nil
+}
+
+// recordRateLimit parses the x-ratelimit-* headers off a response so the
+// next call to waitForRateLimit can pace accordingly.
+func (c *Client) recordRateLimit(h http.Header) {
+	remaining, err := strconv.ParseFloat(h.Get("x-ratelimit-remaining"), 64)
+	if err != nil {
+		return
+	}
+	resetSeconds, err := strconv.ParseFloat(h.Get("x-ratelimit-reset"), 64)
+	if err != nil {
+		return
+	}
+
+	c.rateMu.Lock()
+	c.rateRemaining = int(remaining)
+	c.rateReset = time.Now().Add(time.Duration(resetSeconds) * time.Second)
+	c.rateMu.Unlock()
+}
+
+// waitForRateLimit blocks until the rate-limit reset window if the last
+// known remaining-requests count has dropped below rateLimitBuffer.
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	c.rateMu.Lock()
+	remaining, reset := c.rateRemaining, c.rateReset
+	c.rateMu.Unlock()
+
+	if remaining < 0 || remaining >= rateLimitBuffer {
+		return nil
+	}
+	wait := time.Until(reset)
+	if wait <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
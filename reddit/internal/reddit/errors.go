@@ -0,0 +1,13 @@
+package reddit
+
+import "errors"
+
+// ErrOAuthRevoked is returned when Reddit responds with 401/403, indicating
+// the cached access token is no longer valid (revoked or expired app
+// credentials).
+var ErrOAuthRevoked = errors.New("reddit: oauth token revoked or unauthorized")
+
+// ErrSubredditNotFound is returned when Reddit responds 404 for a
+// subreddit listing request (the subreddit is banned, private, or
+// misspelled).
+var ErrSubredditNotFound = errors.New("reddit: subreddit not found")
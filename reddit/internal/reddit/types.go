@@ -0,0 +1,35 @@
+package reddit
+
+// AuthResponse is Reddit's OAuth token response.
+type AuthResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+// Listing is a Reddit "Listing" response, e.g. the result of a
+// subreddit /new request.
+type Listing struct {
+	Data struct {
+		Children []Child `json:"children"`
+	} `json:"data"`
+}
+
+// Child wraps a single Post inside a Listing, matching Reddit's
+// kind/data envelope.
+type Child struct {
+	Data Post `json:"data"`
+}
+
+// Post is the subset of a Reddit submission's fields the monitor cares
+// about.
+type Post struct {
+	Name       string  `json:"name"` // fullname, e.g. "t3_abc123" - unique across Reddit
+	Title      string  `json:"title"`
+	Author     string  `json:"author"`
+	Score      int     `json:"score"`
+	CreatedUTC float64 `json:"created_utc"`
+	Permalink  string  `json:"permalink"`
+	URL        string  `json:"url"`
+}
@@ -0,0 +1,51 @@
+package seen
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// BadgerStore persists seen post IDs to a local BadgerDB directory,
+// relying on Badger's native key TTLs to self-prune.
+type BadgerStore struct {
+	db *badger.DB
+}
+
+// NewBadgerStore opens (creating if necessary) a BadgerDB directory at
+// dir.
+func NewBadgerStore(dir string) (*BadgerStore, error) {
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	if err != nil {
+		return nil, err
+	}
+	return &BadgerStore{db: db}, nil
+}
+
+func (s *BadgerStore) Has(id string) (bool, error) {
+	var seen bool
+	err := s.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte(id))
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		seen = true
+		return nil
+	})
+	return seen, err
+}
+
+func (s *BadgerStore) Mark(id string, ttl time.Duration) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		entry := badger.NewEntry([]byte(id), []byte{1}).WithTTL(ttl)
+		return txn.SetEntry(entry)
+	})
+}
+
+func (s *BadgerStore) Close() error {
+	return s.db.Close()
+}
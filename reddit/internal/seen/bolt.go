@@ -0,0 +1,67 @@
+package seen
+
+import (
+	"encoding/binary"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var seenBucket = []byte("seen")
+
+// BoltStore persists seen post IDs to a local BoltDB file. It is the
+// default Store for single-instance deployments.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a BoltDB file at path.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(seenBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) Has(id string) (bool, error) {
+	var seen bool
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(seenBucket)
+		v := b.Get([]byte(id))
+		if v == nil {
+			return nil
+		}
+
+		expiry := time.Unix(int64(binary.BigEndian.Uint64(v)), 0)
+		if time.Now().After(expiry) {
+			return b.Delete([]byte(id))
+		}
+		seen = true
+		return nil
+	})
+	return seen, err
+}
+
+func (s *BoltStore) Mark(id string, ttl time.Duration) error {
+	expiry := make([]byte, 8)
+	binary.BigEndian.PutUint64(expiry, uint64(time.Now().Add(ttl).Unix()))
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(seenBucket).Put([]byte(id), expiry)
+	})
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
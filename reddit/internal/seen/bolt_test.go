@@ -0,0 +1,75 @@
+package seen
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestBoltStore(t *testing.T) *BoltStore {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "seen.db")
+	store, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltStore() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("closing store: %v", err)
+		}
+	})
+	return store
+}
+
+func TestBoltStoreMarkAndHas(t *testing.T) {
+	store := newTestBoltStore(t)
+
+	seen, err := store.Has("t3_abc")
+	if err != nil {
+		t.Fatalf("Has() error = %v", err)
+	}
+	if seen {
+		t.Fatal("expected an unmarked ID to be unseen")
+	}
+
+	if err := store.Mark("t3_abc", time.Hour); err != nil {
+		t.Fatalf("Mark() error = %v", err)
+	}
+
+	seen, err = store.Has("t3_abc")
+	if err != nil {
+		t.Fatalf("Has() error = %v", err)
+	}
+	if !seen {
+		t.Fatal("expected a marked, unexpired ID to be seen")
+	}
+}
+
+func TestBoltStoreExpiry(t *testing.T) {
+	store := newTestBoltStore(t)
+
+	if err := store.Mark("t3_expired", -time.Second); err != nil {
+		t.Fatalf("Mark() error = %v", err)
+	}
+
+	seen, err := store.Has("t3_expired")
+	if err != nil {
+		t.Fatalf("Has() error = %v", err)
+	}
+	if seen {
+		t.Fatal("expected a post marked with an already-elapsed TTL to be unseen")
+	}
+
+	// Has should have pruned the expired entry, not just reported it as
+	// unseen; marking again afterwards must still succeed.
+	if err := store.Mark("t3_expired", time.Hour); err != nil {
+		t.Fatalf("re-marking after expiry: %v", err)
+	}
+	seen, err = store.Has("t3_expired")
+	if err != nil {
+		t.Fatalf("Has() error = %v", err)
+	}
+	if !seen {
+		t.Fatal("expected the re-marked ID to be seen")
+	}
+}
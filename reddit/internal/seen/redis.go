@@ -0,0 +1,57 @@
+package seen
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore persists seen post IDs in Redis, letting the TTL be
+// enforced by the server. This is the right choice when several monitor
+// instances share one seen-post history.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore connects to a Redis server at addr, authenticating with
+// password (empty for none) and selecting db.
+func NewRedisStore(addr, password string, db int) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &RedisStore{client: client}, nil
+}
+
+func (s *RedisStore) Has(id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	n, err := s.client.Exists(ctx, id).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (s *RedisStore) Mark(id string, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.client.Set(ctx, id, 1, ttl).Err()
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
@@ -0,0 +1,23 @@
+// Package seen tracks which Reddit post IDs have already triggered a
+// notification, so a monitor that runs on a schedule doesn't re-notify on
+// posts it already sent.
+package seen
+
+import "time"
+
+// DefaultTTL is how long a post ID is remembered before the store is
+// allowed to prune it. 14 days comfortably outlives any reasonable cron
+// schedule while keeping the store from growing unbounded.
+const DefaultTTL = 14 * 24 * time.Hour
+
+// Store records which post IDs have already been notified on.
+// Implementations self-prune entries once their TTL elapses.
+type Store interface {
+	// Has reports whether id was previously marked and has not yet
+	// expired.
+	Has(id string) (bool, error)
+	// Mark records id as seen for the given ttl.
+	Mark(id string, ttl time.Duration) error
+	// Close releases any resources held by the store.
+	Close() error
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the Prometheus collectors the monitor reports, and
+// tracks the request-count baseline needed to turn the Reddit client's
+// cumulative counter into a delta per run.
+type Metrics struct {
+	redditAPIRequests  prometheus.Counter
+	rateLimitRemaining prometheus.Gauge
+	postsMatched       *prometheus.CounterVec
+	notificationsSent  *prometheus.CounterVec
+	lastRunTimestamp   prometheus.Gauge
+
+	lastRequestCount int64
+}
+
+// NewMetrics builds and registers the monitor's Prometheus collectors
+// against the default registry.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		redditAPIRequests: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "reddit_api_requests_total",
+			Help: "Total number of HTTP requests made to the Reddit API.",
+		}),
+		rateLimitRemaining: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "reddit_api_rate_limit_remaining",
+			Help: "Remaining requests in the current Reddit rate-limit window.",
+		}),
+		postsMatched: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "posts_matched_total",
+			Help: "Total number of posts matched, by rule.",
+		}, []string{"rule"}),
+		notificationsSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "notifications_sent_total",
+			Help: "Total number of notifications sent, by backend and outcome.",
+		}, []string{"backend", "status"}),
+		lastRunTimestamp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "last_run_timestamp_seconds",
+			Help: "Unix timestamp of the last completed monitor run.",
+		}),
+	}
+
+	prometheus.MustRegister(
+		m.redditAPIRequests,
+		m.rateLimitRemaining,
+		m.postsMatched,
+		m.notificationsSent,
+		m.lastRunTimestamp,
+	)
+	return m
+}
+
+// recordRequestCount adds the requests issued since the last call to the
+// reddit_api_requests_total counter.
+func (m *Metrics) recordRequestCount(total int64) {
+	if delta := total - m.lastRequestCount; delta > 0 {
+		m.redditAPIRequests.Add(float64(delta))
+	}
+	m.lastRequestCount = total
+}
+
+// Handler returns the HTTP handler serving /metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// healthzHandler reports liveness for container/k8s probes.
+func healthzHandler(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
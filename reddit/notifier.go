@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Notifier delivers a notification about matched posts through some backend
+// (SMTP, Mailgun, Slack, a generic webhook, or a fan-out of several).
+type Notifier interface {
+	Send(subject, body string, posts []Post) error
+}
+
+// NewNotifier builds the Notifier selected by cfg.NotifierType.
+func NewNotifier(cfg *Config) (Notifier, error) {
+	switch cfg.NotifierType {
+	case "", "smtp":
+		return newSMTPNotifier(cfg)
+	case "mailgun":
+		return newMailgunNotifier(cfg)
+	case "slack":
+		return newSlackNotifier(cfg)
+	case "webhook":
+		return newWebhookNotifier(cfg)
+	case "multi":
+		return newMultiNotifier(cfg)
+	default:
+		return nil, fmt.Errorf("unknown NOTIFIER backend: %q", cfg.NotifierType)
+	}
+}
+
+// newMultiNotifier builds one Notifier per name in cfg.NotifierBackends and
+// wraps them in a MultiNotifier.
+func newMultiNotifier(cfg *Config) (Notifier, error) {
+	if len(cfg.NotifierBackends) == 0 {
+		return nil, fmt.Errorf("NOTIFIER=multi requires NOTIFIER_BACKENDS to list at least one backend")
+	}
+
+	notifiers := make([]Notifier, 0, len(cfg.NotifierBackends))
+	for _, backend := range cfg.NotifierBackends {
+		sub := *cfg
+		sub.NotifierType = strings.ToLower(strings.TrimSpace(backend))
+		n, err := NewNotifier(&sub)
+		if err != nil {
+			return nil, fmt.Errorf("building %q backend for multi notifier: %w", backend, err)
+		}
+		notifiers = append(notifiers, n)
+	}
+
+	return &MultiNotifier{Notifiers: notifiers}, nil
+}
+
+// MultiNotifier fans a notification out to several backends in parallel and
+// aggregates any errors they return.
+type MultiNotifier struct {
+	Notifiers []Notifier
+}
+
+func (m *MultiNotifier) Send(subject, body string, posts []Post) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	for _, n := range m.Notifiers {
+		wg.Add(1)
+		go func(n Notifier) {
+			defer wg.Done()
+			if err := n.Send(subject, body, posts); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Errorf("%d of %d notifiers failed: %s", len(errs), len(m.Notifiers), strings.Join(msgs, "; "))
+}
+
+// SMTPNotifier sends notifications as a multipart/alternative (plain
+// text + HTML) email over SMTP, with a CSV attachment of the matched
+// posts, using implicit TLS on the configured port or a STARTTLS
+// upgrade on port 587.
+type SMTPNotifier struct {
+	Host, Port   string
+	From, To     string
+	User, Pass   string
+	TemplatePath string
+}
+
+func newSMTPNotifier(cfg *Config) (*SMTPNotifier, error) {
+	if cfg.SMTPHost == "" || cfg.SMTPPort == "" || cfg.EmailFrom == "" || cfg.EmailTo == "" || cfg.EmailUser == "" || cfg.EmailPass == "" {
+		return nil, fmt.Errorf("missing SMTP/email configuration in environment variables")
+	}
+	return &SMTPNotifier{
+		Host:         cfg.SMTPHost,
+		Port:         cfg.SMTPPort,
+		From:         cfg.EmailFrom,
+		To:           cfg.EmailTo,
+		User:         cfg.EmailUser,
+		Pass:         cfg.EmailPass,
+		TemplatePath: cfg.EmailTemplatePath,
+	}, nil
+}
+
+func (n *SMTPNotifier) Send(subject, body string, posts []Post) error {
+	htmlBody, err := renderHTMLBody(n.TemplatePath, posts)
+	if err != nil {
+		return err
+	}
+
+	var attachments []EmailAttachment
+	if len(posts) > 0 {
+		att, err := csvAttachment("matched_posts.csv", posts)
+		if err != nil {
+			return fmt.Errorf("building CSV attachment: %w", err)
+		}
+		attachments = append(attachments, att)
+	}
+
+	message, err := buildMIMEMessage(n.From, EmailMessage{
+		To:          n.To,
+		Subject:     subject,
+		Body:        body,
+		HTMLBody:    htmlBody,
+		Attachments: attachments,
+	})
+	if err != nil {
+		return fmt.Errorf("building email message: %w", err)
+	}
+
+	auth := smtp.PlainAuth("", n.User, n.Pass, n.Host)
+	addr := net.JoinHostPort(n.Host, n.Port)
+	tlsConfig := &tls.Config{ServerName: n.Host}
+
+	var c *smtp.Client
+	if n.Port == "587" {
+		// Port 587 expects a plaintext connection that is then upgraded
+		// via STARTTLS, rather than implicit TLS from the first byte.
+		conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+		if err != nil {
+			return err
+		}
+		c, err = smtp.NewClient(conn, n.Host)
+		if err != nil {
+			return err
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	} else {
+		conn, err := tls.Dial("tcp", addr, tlsConfig)
+		if err != nil {
+			return err
+		}
+		c, err = smtp.NewClient(conn, n.Host)
+		if err != nil {
+			return err
+		}
+	}
+	defer c.Close()
+
+	if err := c.Auth(auth); err != nil {
+		return err
+	}
+	if err := c.Mail(n.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(n.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(message)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// MailgunNotifier sends notifications through the Mailgun HTTP API.
+type MailgunNotifier struct {
+	Domain, APIKey string
+	From, To       string
+	TemplatePath   string
+	httpClient     *http.Client
+}
+
+func newMailgunNotifier(cfg *Config) (*MailgunNotifier, error) {
+	if cfg.MailgunDomain == "" || cfg.MailgunAPIKey == "" || cfg.MailgunFrom == "" || cfg.MailgunTo == "" {
+		return nil, fmt.Errorf("missing Mailgun configuration in environment variables")
+	}
+	return &MailgunNotifier{
+		Domain:       cfg.MailgunDomain,
+		APIKey:       cfg.MailgunAPIKey,
+		From:         cfg.MailgunFrom,
+		To:           cfg.MailgunTo,
+		TemplatePath: cfg.EmailTemplatePath,
+		httpClient:   &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+func (n *MailgunNotifier) Send(subject, body string, posts []Post) error {
+	htmlBody, err := renderHTMLBody(n.TemplatePath, posts)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"from":    {n.From},
+		"to":      {n.To},
+		"subject": {subject},
+		"text":    {body},
+		"html":    {htmlBody},
+	}
+
+	endpoint := fmt.Sprintf("https://api.mailgun.net/v3/%s/messages", n.Domain)
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("api", n.APIKey)
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("mailgun: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// SlackNotifier posts notifications to a Slack incoming webhook.
+type SlackNotifier struct {
+	WebhookURL string
+	httpClient *http.Client
+}
+
+func newSlackNotifier(cfg *Config) (*SlackNotifier, error) {
+	if cfg.SlackWebhookURL == "" {
+		return nil, fmt.Errorf("missing SLACK_WEBHOOK_URL in environment variables")
+	}
+	return &SlackNotifier{
+		WebhookURL: cfg.SlackWebhookURL,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+func (n *SlackNotifier) Send(subject, body string, _ []Post) error {
+	payload, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", subject, body),
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.httpClient.Post(n.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// WebhookNotifier posts a generic JSON payload to an arbitrary endpoint.
+type WebhookNotifier struct {
+	URL        string
+	httpClient *http.Client
+}
+
+func newWebhookNotifier(cfg *Config) (*WebhookNotifier, error) {
+	if cfg.WebhookURL == "" {
+		return nil, fmt.Errorf("missing WEBHOOK_URL in environment variables")
+	}
+	return &WebhookNotifier{
+		URL:        cfg.WebhookURL,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+func (n *WebhookNotifier) Send(subject, body string, posts []Post) error {
+	payload, err := json.Marshal(struct {
+		Subject string `json:"subject"`
+		Body    string `json:"body"`
+		Posts   []Post `json:"posts"`
+	}{Subject: subject, Body: body, Posts: posts})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.httpClient.Post(n.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: unexpected status %s", resp.Status)
+	}
+	return nil
+}
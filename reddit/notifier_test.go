@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeNotifier is a Notifier test double that either succeeds or returns
+// a fixed error, recording the subject/body/posts it was sent.
+type fakeNotifier struct {
+	name string
+	err  error
+
+	gotSubject string
+	gotBody    string
+	gotPosts   []Post
+}
+
+func (f *fakeNotifier) Send(subject, body string, posts []Post) error {
+	f.gotSubject, f.gotBody, f.gotPosts = subject, body, posts
+	return f.err
+}
+
+func TestMultiNotifierSendAllSucceed(t *testing.T) {
+	a := &fakeNotifier{name: "a"}
+	b := &fakeNotifier{name: "b"}
+	m := &MultiNotifier{Notifiers: []Notifier{a, b}}
+
+	posts := []Post{{ID: "t3_1", Title: "hello"}}
+	if err := m.Send("subj", "body", posts); err != nil {
+		t.Fatalf("Send() error = %v, want nil", err)
+	}
+	if a.gotSubject != "subj" || b.gotSubject != "subj" {
+		t.Error("expected every backend to receive the subject")
+	}
+}
+
+func TestMultiNotifierSendAggregatesErrors(t *testing.T) {
+	a := &fakeNotifier{name: "a", err: fmt.Errorf("smtp down")}
+	b := &fakeNotifier{name: "b"}
+	c := &fakeNotifier{name: "c", err: fmt.Errorf("webhook timeout")}
+	m := &MultiNotifier{Notifiers: []Notifier{a, b, c}}
+
+	err := m.Send("subj", "body", nil)
+	if err == nil {
+		t.Fatal("expected an aggregate error when some backends fail")
+	}
+	if !strings.Contains(err.Error(), "2 of 3 notifiers failed") {
+		t.Errorf("error = %q, want it to report 2 of 3 failures", err.Error())
+	}
+	if !strings.Contains(err.Error(), "smtp down") || !strings.Contains(err.Error(), "webhook timeout") {
+		t.Errorf("error = %q, want it to include both underlying errors", err.Error())
+	}
+}
+
+func TestMultiNotifierSendStillCallsEveryBackendOnFailure(t *testing.T) {
+	a := &fakeNotifier{name: "a", err: fmt.Errorf("fails first")}
+	b := &fakeNotifier{name: "b"}
+	m := &MultiNotifier{Notifiers: []Notifier{a, b}}
+
+	if err := m.Send("subj", "body", nil); err == nil {
+		t.Fatal("expected an error since backend a fails")
+	}
+	if b.gotSubject != "subj" {
+		t.Error("expected backend b to still be sent to despite backend a failing")
+	}
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	internalreddit "github.com/tarun1820/go-learnings/reddit/internal/reddit"
+	internalseen "github.com/tarun1820/go-learnings/reddit/internal/seen"
+)
+
+// ruleWorkerCount bounds how many rules EvaluateRules evaluates at once.
+const ruleWorkerCount = 5
+
+// Rule describes a single subreddit-monitoring rule: what to watch, what
+// counts as a match, and which notifier backends to alert on a hit.
+type Rule struct {
+	Name            string        `mapstructure:"name"`
+	Subreddits      []string      `mapstructure:"subreddits"`
+	Keywords        []string      `mapstructure:"keywords"`
+	Patterns        []string      `mapstructure:"patterns"`
+	ExcludeKeywords []string      `mapstructure:"exclude_keywords"`
+	MinScore        int           `mapstructure:"min_score"`
+	MaxAge          time.Duration `mapstructure:"max_age"`
+	AuthorAllow     []string      `mapstructure:"author_allow"`
+	AuthorDeny      []string      `mapstructure:"author_deny"`
+	Notifiers       []string      `mapstructure:"notifiers"`
+
+	compiled []*regexp.Regexp
+}
+
+// LoadRules reads a YAML or JSON rules file (the format is inferred from
+// its extension) shaped like:
+//
+//	rules:
+//	  - name: furniture
+//	    subreddits: [HyderabadBuySell]
+//	    keywords: [table, chair]
+//	    exclude_keywords: [wanted]
+//	    notifiers: [smtp]
+func LoadRules(path string) ([]Rule, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("reading rules config %s: %w", path, err)
+	}
+
+	var parsed struct {
+		Rules []Rule `mapstructure:"rules"`
+	}
+	if err := v.Unmarshal(&parsed); err != nil {
+		return nil, fmt.Errorf("parsing rules config %s: %w", path, err)
+	}
+
+	for i := range parsed.Rules {
+		rule := &parsed.Rules[i]
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d is missing a name", i)
+		}
+		for _, pattern := range rule.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: invalid pattern %q: %w", rule.Name, pattern, err)
+			}
+			rule.compiled = append(rule.compiled, re)
+		}
+	}
+
+	return parsed.Rules, nil
+}
+
+// RuleMatch is a Post that satisfied one or more rules.
+type RuleMatch struct {
+	Post      Post
+	RuleNames []string
+}
+
+// EvaluateRules fetches and filters posts for every rule concurrently
+// through a bounded worker pool, then merges the results: a post that
+// satisfies several rules is deduplicated into a single RuleMatch tagging
+// every rule it hit. A rule that errors (e.g. a banned or misspelled
+// subreddit) is logged and skipped rather than failing the whole batch,
+// so one bad rule can't permanently block every other rule's matches.
+func EvaluateRules(ctx context.Context, client *internalreddit.Client, store internalseen.Store, rules []Rule) ([]RuleMatch, error) {
+	type result struct {
+		rule    Rule
+		matches []Post
+		err     error
+	}
+
+	ruleCh := make(chan Rule)
+	resultCh := make(chan result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < ruleWorkerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rule := range ruleCh {
+				matches, err := evaluateRule(ctx, client, store, rule)
+				resultCh <- result{rule: rule, matches: matches, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, rule := range rules {
+			ruleCh <- rule
+		}
+		close(ruleCh)
+	}()
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	merged := make(map[string]*RuleMatch)
+	for res := range resultCh {
+		if res.err != nil {
+			log.Printf("Skipping rule %q: %v", res.rule.Name, res.err)
+			continue
+		}
+		for _, post := range res.matches {
+			if rm, ok := merged[post.ID]; ok {
+				rm.RuleNames = append(rm.RuleNames, res.rule.Name)
+			} else {
+				merged[post.ID] = &RuleMatch{Post: post, RuleNames: []string{res.rule.Name}}
+			}
+		}
+	}
+
+	out := make([]RuleMatch, 0, len(merged))
+	for _, rm := range merged {
+		out = append(out, *rm)
+	}
+	return out, nil
+}
+
+// evaluateRule fetches the newest posts for each of the rule's
+// subreddits and returns the ones that satisfy the rule and have not
+// already been notified on.
+func evaluateRule(ctx context.Context, client *internalreddit.Client, store internalseen.Store, rule Rule) ([]Post, error) {
+	var matches []Post
+
+	for _, subreddit := range rule.Subreddits {
+		listing, err := client.SubredditNew(ctx, subreddit, 100)
+		if err != nil {
+			return nil, fmt.Errorf("fetching r/%s: %w", subreddit, err)
+		}
+
+		for _, child := range listing.Data.Children {
+			post := child.Data
+			if !ruleMatchesPost(rule, post) {
+				continue
+			}
+
+			seen, err := store.Has(post.Name)
+			if err != nil {
+				return nil, fmt.Errorf("checking seen store for %s: %w", post.Name, err)
+			}
+			if seen {
+				continue
+			}
+
+			matches = append(matches, Post{
+				ID:        post.Name,
+				Title:     post.Title,
+				Permalink: "https://reddit.com" + post.Permalink,
+				Created:   time.Unix(int64(post.CreatedUTC), 0).UTC(),
+			})
+		}
+	}
+
+	return matches, nil
+}
+
+// ruleMatchesPost reports whether post satisfies rule's score/age bounds,
+// author lists, and include/exclude keywords or patterns.
+func ruleMatchesPost(rule Rule, post internalreddit.Post) bool {
+	if rule.MinScore > 0 && post.Score < rule.MinScore {
+		return false
+	}
+	if rule.MaxAge > 0 && time.Since(time.Unix(int64(post.CreatedUTC), 0)) > rule.MaxAge {
+		return false
+	}
+	if len(rule.AuthorDeny) > 0 && containsFold(rule.AuthorDeny, post.Author) {
+		return false
+	}
+	if len(rule.AuthorAllow) > 0 && !containsFold(rule.AuthorAllow, post.Author) {
+		return false
+	}
+
+	title := strings.ToLower(post.Title)
+	for _, kw := range rule.ExcludeKeywords {
+		if strings.Contains(title, strings.ToLower(kw)) {
+			return false
+		}
+	}
+
+	if len(rule.Keywords) == 0 && len(rule.compiled) == 0 {
+		return true
+	}
+	for _, kw := range rule.Keywords {
+		if strings.Contains(title, strings.ToLower(kw)) {
+			return true
+		}
+	}
+	for _, re := range rule.compiled {
+		if re.MatchString(post.Title) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether list contains s, case-insensitively.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// routeAndNotify groups matches by the notifier backend(s) their rules
+// name (falling back to cfg.NotifierType when a rule names none), then
+// sends one notification per backend with the body grouped by rule. It
+// tries every backend even if one fails, reporting outcomes to metrics
+// (if non-nil). It returns the set of post IDs that reached every backend
+// routed to them, plus an aggregate error describing any backend that
+// failed - callers should only mark the returned posts as seen, so a post
+// a failed backend never got isn't silently dropped from the next run.
+func routeAndNotify(cfg *Config, rules []Rule, matches []RuleMatch, metrics *Metrics) (map[string]bool, error) {
+	ruleNotifiers := make(map[string][]string, len(rules))
+	for _, rule := range rules {
+		names := rule.Notifiers
+		if len(names) == 0 {
+			names = []string{cfg.NotifierType}
+		}
+		ruleNotifiers[rule.Name] = names
+	}
+
+	byNotifier := make(map[string][]RuleMatch)
+	for _, match := range matches {
+		targets := make(map[string]bool)
+		for _, ruleName := range match.RuleNames {
+			for _, notifierName := range ruleNotifiers[ruleName] {
+				targets[notifierName] = true
+			}
+		}
+		for notifierName := range targets {
+			byNotifier[notifierName] = append(byNotifier[notifierName], match)
+		}
+	}
+
+	sent := make(map[string]bool, len(matches))
+	failed := make(map[string]bool)
+	var errs []string
+	for notifierName, notifierMatches := range byNotifier {
+		if err := sendToNotifier(cfg, notifierName, notifierMatches); err != nil {
+			if metrics != nil {
+				metrics.notificationsSent.WithLabelValues(notifierName, "failure").Inc()
+			}
+			errs = append(errs, fmt.Sprintf("%s: %v", notifierName, err))
+			for _, match := range notifierMatches {
+				failed[match.Post.ID] = true
+			}
+			continue
+		}
+		if metrics != nil {
+			metrics.notificationsSent.WithLabelValues(notifierName, "success").Inc()
+		}
+		for _, match := range notifierMatches {
+			sent[match.Post.ID] = true
+		}
+	}
+	for postID := range failed {
+		delete(sent, postID)
+	}
+
+	if len(errs) > 0 {
+		return sent, fmt.Errorf("%d of %d notifier backends failed: %s", len(errs), len(byNotifier), strings.Join(errs, "; "))
+	}
+	return sent, nil
+}
+
+// sendToNotifier builds the named notifier backend and sends it matches
+// grouped by rule.
+func sendToNotifier(cfg *Config, notifierName string, matches []RuleMatch) error {
+	sub := *cfg
+	sub.NotifierType = notifierName
+	notifier, err := NewNotifier(&sub)
+	if err != nil {
+		return fmt.Errorf("building notifier: %w", err)
+	}
+
+	body := ComposeRuleEmailBody(matches)
+	return notifier.Send("Reddit Alert: Matching Posts Found", body, postsOf(matches))
+}
+
+// ComposeRuleEmailBody renders matches grouped under a heading per rule
+// name, so a post matching several rules appears once per rule.
+func ComposeRuleEmailBody(matches []RuleMatch) string {
+	byRule := make(map[string][]RuleMatch)
+	for _, match := range matches {
+		for _, name := range match.RuleNames {
+			byRule[name] = append(byRule[name], match)
+		}
+	}
+
+	names := make([]string, 0, len(byRule))
+	for name := range byRule {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buffer bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&buffer, "== %s ==\n\n", name)
+		for _, match := range byRule[name] {
+			fmt.Fprintf(&buffer, "- %s\n  %s\n  Posted at (UTC): %s\n\n",
+				match.Post.Title, match.Post.Permalink, match.Post.Created.Format(time.RFC1123))
+		}
+	}
+	return buffer.String()
+}
+
+// postsOf extracts the Post from each RuleMatch.
+func postsOf(matches []RuleMatch) []Post {
+	posts := make([]Post, len(matches))
+	for i, match := range matches {
+		posts[i] = match.Post
+	}
+	return posts
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	internalreddit "github.com/tarun1820/go-learnings/reddit/internal/reddit"
+)
+
+func TestRuleMatchesPost(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		rule Rule
+		post internalreddit.Post
+		want bool
+	}{
+		{
+			name: "no filters matches everything",
+			rule: Rule{},
+			post: internalreddit.Post{Title: "anything", CreatedUTC: float64(now.Unix())},
+			want: true,
+		},
+		{
+			name: "below min score is rejected",
+			rule: Rule{MinScore: 10},
+			post: internalreddit.Post{Score: 5, CreatedUTC: float64(now.Unix())},
+			want: false,
+		},
+		{
+			name: "older than max age is rejected",
+			rule: Rule{MaxAge: time.Hour},
+			post: internalreddit.Post{CreatedUTC: float64(now.Add(-2 * time.Hour).Unix())},
+			want: false,
+		},
+		{
+			name: "denied author is rejected even if keywords match",
+			rule: Rule{Keywords: []string{"chair"}, AuthorDeny: []string{"Spammer"}},
+			post: internalreddit.Post{Title: "selling a chair", Author: "spammer", CreatedUTC: float64(now.Unix())},
+			want: false,
+		},
+		{
+			name: "author not in allow list is rejected",
+			rule: Rule{AuthorAllow: []string{"trusted"}},
+			post: internalreddit.Post{Author: "someone_else", CreatedUTC: float64(now.Unix())},
+			want: false,
+		},
+		{
+			name: "exclude keyword wins over matching keyword",
+			rule: Rule{Keywords: []string{"table"}, ExcludeKeywords: []string{"wanted"}},
+			post: internalreddit.Post{Title: "wanted: table", CreatedUTC: float64(now.Unix())},
+			want: false,
+		},
+		{
+			name: "matching keyword is case-insensitive",
+			rule: Rule{Keywords: []string{"Chair"}},
+			post: internalreddit.Post{Title: "selling a CHAIR cheap", CreatedUTC: float64(now.Unix())},
+			want: true,
+		},
+		{
+			name: "no keyword or pattern match is rejected",
+			rule: Rule{Keywords: []string{"table"}},
+			post: internalreddit.Post{Title: "selling a lamp", CreatedUTC: float64(now.Unix())},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ruleMatchesPost(tt.rule, tt.post); got != tt.want {
+				t.Errorf("ruleMatchesPost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleMatchesPostPattern(t *testing.T) {
+	rule := Rule{Patterns: []string{`(?i)\bfree\b`}, compiled: []*regexp.Regexp{regexp.MustCompile(`(?i)\bfree\b`)}}
+
+	if !ruleMatchesPost(rule, internalreddit.Post{Title: "Free couch, pickup only"}) {
+		t.Error("expected pattern to match")
+	}
+	if ruleMatchesPost(rule, internalreddit.Post{Title: "freedom tower poster"}) {
+		t.Error("expected word-boundary pattern not to match a substring")
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.yaml")
+	contents := `
+rules:
+  - name: furniture
+    subreddits: [HyderabadBuySell]
+    keywords: [table, chair]
+    exclude_keywords: [wanted]
+    notifiers: [smtp]
+  - name: electronics
+    subreddits: [IndiaBuySell]
+    patterns: ["(?i)\\blaptop\\b"]
+`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing rules file: %v", err)
+	}
+
+	rules, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(rules))
+	}
+	if rules[0].Name != "furniture" || len(rules[0].Subreddits) != 1 {
+		t.Errorf("unexpected first rule: %+v", rules[0])
+	}
+	if len(rules[1].compiled) != 1 {
+		t.Errorf("expected electronics rule to compile its one pattern, got %d", len(rules[1].compiled))
+	}
+}
+
+func TestLoadRulesRejectsMissingName(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.yaml")
+	contents := `
+rules:
+  - subreddits: [test]
+`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing rules file: %v", err)
+	}
+
+	if _, err := LoadRules(path); err == nil {
+		t.Fatal("expected an error for a rule missing a name, got nil")
+	}
+}
+
+func TestLoadRulesRejectsInvalidPattern(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.yaml")
+	contents := `
+rules:
+  - name: broken
+    patterns: ["("]
+`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing rules file: %v", err)
+	}
+
+	if _, err := LoadRules(path); err == nil {
+		t.Fatal("expected an error for an invalid regex pattern, got nil")
+	}
+}
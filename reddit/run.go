@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	internalreddit "github.com/tarun1820/go-learnings/reddit/internal/reddit"
+	internalseen "github.com/tarun1820/go-learnings/reddit/internal/seen"
+)
+
+// runOnce reloads the rules config, evaluates it against Reddit, routes
+// any matches to their notifiers, marks sent posts as seen, and updates
+// metrics. It is the unit of work both the one-shot CLI and the daemon's
+// cron schedule repeat. ctx is honored by the Reddit fetches, so
+// cancelling it (e.g. on SIGINT) aborts an in-flight run.
+func runOnce(ctx context.Context, cfg *Config, rulesPath string, client *internalreddit.Client, store internalseen.Store, metrics *Metrics) error {
+	rules, err := LoadRules(rulesPath)
+	if err != nil {
+		return fmt.Errorf("loading rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("rules config defines no rules")
+	}
+
+	matches, err := EvaluateRules(ctx, client, store, rules)
+	metrics.recordRequestCount(client.RequestCount())
+	metrics.rateLimitRemaining.Set(float64(client.RateLimitRemaining()))
+	if err != nil {
+		return fmt.Errorf("evaluating rules: %w", err)
+	}
+
+	for _, match := range matches {
+		for _, ruleName := range match.RuleNames {
+			metrics.postsMatched.WithLabelValues(ruleName).Inc()
+		}
+	}
+
+	if len(matches) == 0 {
+		log.Println("No matching posts found")
+	} else {
+		sent, notifyErr := routeAndNotify(cfg, rules, matches, metrics)
+		for _, match := range matches {
+			if !sent[match.Post.ID] {
+				continue
+			}
+			if err := store.Mark(match.Post.ID, internalseen.DefaultTTL); err != nil {
+				log.Printf("Failed to mark post %s as seen: %v", match.Post.ID, err)
+			}
+		}
+		if notifyErr != nil {
+			metrics.lastRunTimestamp.SetToCurrentTime()
+			return fmt.Errorf("delivering notifications: %w", notifyErr)
+		}
+		log.Println("Notifications sent successfully")
+	}
+
+	metrics.lastRunTimestamp.SetToCurrentTime()
+	return nil
+}
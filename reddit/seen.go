@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	internalseen "github.com/tarun1820/go-learnings/reddit/internal/seen"
+)
+
+// newSeenStore builds the seen.Store selected by cfg.SeenStoreType.
+func newSeenStore(cfg *Config) (internalseen.Store, error) {
+	switch cfg.SeenStoreType {
+	case "", "bolt":
+		return internalseen.NewBoltStore(cfg.SeenStorePath)
+	case "badger":
+		return internalseen.NewBadgerStore(cfg.SeenStorePath)
+	case "redis":
+		db, err := strconv.Atoi(envOrDefault("SEEN_REDIS_DB", "0"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SEEN_REDIS_DB: %w", err)
+		}
+		return internalseen.NewRedisStore(cfg.SeenRedisAddr, cfg.SeenRedisPassword, db)
+	default:
+		return nil, fmt.Errorf("unknown SEEN_STORE backend: %q", cfg.SeenStoreType)
+	}
+}